@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/Biswas57/git-contributions-cli/providers"
+)
+
+// buildProviders resolves the enabled remote Provider implementations for
+// the given --source names, skipping "local" (handled directly by
+// processRepositories) and logging, rather than failing, a source whose
+// token isn't configured.
+func buildProviders(sources []string) []providers.Provider {
+	var enabled []providers.Provider
+
+	for _, source := range sources {
+		switch source {
+		case "local":
+			// handled by processRepositories
+		case "github":
+			if token := providers.LoadToken("GH_TOKEN", "github_token"); token != "" {
+				enabled = append(enabled, providers.NewGithubProvider(token))
+			} else {
+				log.Printf("skipping github source: no token (set GH_TOKEN or github_token in ~/.gogitconfig)")
+			}
+		case "gitlab":
+			if token := providers.LoadToken("GL_TOKEN", "gitlab_token"); token != "" {
+				enabled = append(enabled, providers.NewGitlabProvider(token))
+			} else {
+				log.Printf("skipping gitlab source: no token (set GL_TOKEN or gitlab_token in ~/.gogitconfig)")
+			}
+		default:
+			log.Printf("unknown source %q, ignoring", source)
+		}
+	}
+
+	return enabled
+}
+
+// containsSource reports whether `name` is among `sources`.
+func containsSource(sources []string, name string) bool {
+	for _, s := range sources {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeProviderCommits folds a provider's time.Time-keyed results into
+// `commits`, converting each day into the same days-ago index the local
+// scan uses.
+func mergeProviderCommits(commits map[int]int, opts StatsOptions, remote map[time.Time]int) {
+	until := opts.resolvedUntil()
+	offset := calcOffset(until)
+	maxDays := opts.windowDays()
+
+	for day, count := range remote {
+		daysAgo := countDaysSinceDate(day, until, maxDays)
+		if daysAgo != outOfRange {
+			commits[daysAgo+offset] += count
+		}
+	}
+}
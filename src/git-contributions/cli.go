@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// dateLayout is the expected format for --since/--until flag values.
+const dateLayout = "2006-01-02"
+
+// newRootCmd builds the git-contrib command tree. The root command itself
+// keeps the legacy -add/-path flags working for one release so that
+// existing scripts and muscle memory don't break while people move over
+// to the scan/stats subcommands.
+func newRootCmd() *cobra.Command {
+	var legacyAdd string
+	var legacyPath string
+
+	root := &cobra.Command{
+		Use:   "git-contrib",
+		Short: "Visualise your local Git commit activity",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if legacyAdd != "" {
+				scan(legacyAdd)
+				return nil
+			}
+
+			opts := StatsOptions{Jobs: runtime.NumCPU()}
+			if legacyPath != "" {
+				opts.Authors = []string{legacyPath}
+			}
+			stats(opts)
+			return nil
+		},
+	}
+
+	root.Flags().StringVar(&legacyAdd, "add", "", "deprecated: use \"git-contrib scan <path>\" instead")
+	root.Flags().StringVar(&legacyPath, "path", "", "deprecated: use \"git-contrib stats --email <email>\" instead")
+
+	root.AddCommand(newScanCmd())
+	root.AddCommand(newStatsCmd())
+	root.AddCommand(newListCmd())
+	root.AddCommand(newRemoveCmd())
+	root.AddCommand(newConfigCmd())
+
+	return root
+}
+
+func newScanCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "scan <path>",
+		Short: "Scan a directory tree for Git repositories and track them",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scan(args[0])
+			return nil
+		},
+	}
+}
+
+func newStatsCmd() *cobra.Command {
+	cfg := loadConfig()
+
+	defaultJobs := cfg.Jobs
+	if defaultJobs <= 0 {
+		defaultJobs = runtime.NumCPU()
+	}
+
+	// cfg.Providers only configures which *remote* providers are enabled by
+	// default; local scanning stays on unless the user explicitly opts out
+	// via --source, so a providers-only config can't silently disable it.
+	defaultSources := []string{"local"}
+	for _, p := range cfg.Providers {
+		if p != "local" {
+			defaultSources = append(defaultSources, p)
+		}
+	}
+
+	var email string
+	var authors []string
+	var paths []string
+	var sources []string
+	var since string
+	var until string
+	var jobs int
+	var format string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Print a contribution graph for the tracked repositories",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := StatsOptions{Jobs: jobs, Authors: authors, Paths: paths, Sources: sources, Format: format, Output: output}
+			if email != "" {
+				opts.Authors = append(opts.Authors, email)
+			}
+
+			if since != "" {
+				t, err := time.Parse(dateLayout, since)
+				if err != nil {
+					return fmt.Errorf("invalid --since date %q: %w", since, err)
+				}
+				opts.Since = t
+			}
+
+			if until != "" {
+				t, err := time.Parse(dateLayout, until)
+				if err != nil {
+					return fmt.Errorf("invalid --until date %q: %w", until, err)
+				}
+				opts.Until = t
+			}
+
+			stats(opts)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", cfg.Email, "email address to filter commits by (combined with --author)")
+	cmd.Flags().StringSliceVar(&authors, "author", nil, "author email(s) to filter commits by (repeatable, or comma-separated, to merge identities)")
+	cmd.Flags().StringArrayVar(&paths, "path", nil, "restrict the graph to commits touching files under this path or doublestar glob, e.g. \"frontend/\" or \"**/*.go\" (repeatable)")
+	cmd.Flags().StringSliceVar(&sources, "source", defaultSources, "commit sources to aggregate: local,github,gitlab")
+	cmd.Flags().StringVar(&since, "since", cfg.Since, "only count commits on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&until, "until", "", "only count commits on or before this date (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&jobs, "jobs", defaultJobs, "number of concurrent repo workers")
+	cmd.Flags().StringVar(&format, "format", "ansi", "output format: ansi, json, svg or html")
+	cmd.Flags().StringVar(&output, "output", "", "write output to this file instead of stdout")
+
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List tracked state",
+	}
+
+	list.AddCommand(&cobra.Command{
+		Use:   "repos",
+		Short: "List tracked repositories and their last commit date",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listRepos()
+		},
+	})
+
+	return list
+}
+
+func newRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <path|glob>...",
+		Short: "Stop tracking repositories matching a path or glob",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return removeRepos(args)
+		},
+	}
+}
+
+func newConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "config",
+		Short: "Print the location of the tracked-repos and config files",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Println(reposPath())
+			fmt.Println(configPath())
+			return nil
+		},
+	}
+}
+
+// Execute runs the root command, printing any returned error and exiting
+// with a non-zero status.
+func Execute() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// legacyGitFile is the repos file git-contrib used before it moved to an
+// XDG-compliant location. It's only read once, to migrate into the new
+// location on first run.
+const legacyGitFile = ".gogitlocalstats"
+
+// Config holds the persisted defaults for git-contrib, read once at
+// startup and overridable per invocation by the matching flag.
+type Config struct {
+	Email     string   `toml:"email"`
+	Since     string   `toml:"since"`
+	Jobs      int      `toml:"jobs"`
+	Providers []string `toml:"providers"`
+}
+
+// configDir returns (creating it if necessary) the XDG config directory
+// for git-contrib: $XDG_CONFIG_HOME/git-contrib, falling back to
+// ~/.config/git-contrib.
+func configDir() (string, error) {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".config")
+	}
+
+	dir := filepath.Join(base, "git-contrib")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// reposPath returns the path to the tracked-repos file, migrating a
+// pre-existing ./.gogitlocalstats into place the first time it's found.
+// Falls back to the legacy path if the XDG directory can't be created
+// (e.g. a read-only $HOME).
+func reposPath() string {
+	dir, err := configDir()
+	if err != nil {
+		return legacyGitFile
+	}
+
+	path := filepath.Join(dir, "repos.txt")
+	migrateLegacyRepos(path)
+	return path
+}
+
+// migrateLegacyRepos copies the legacy repos file to newPath the first
+// time newPath doesn't exist yet, leaving the legacy file untouched.
+func migrateLegacyRepos(newPath string) {
+	if _, err := os.Stat(newPath); err == nil {
+		return // already migrated
+	}
+
+	content, err := os.ReadFile(legacyGitFile)
+	if err != nil {
+		return // nothing to migrate
+	}
+
+	if err := os.WriteFile(newPath, content, 0644); err != nil {
+		log.Printf("migrating legacy %s: %v", legacyGitFile, err)
+	}
+}
+
+// configPath returns the path to the persisted TOML config file.
+func configPath() string {
+	dir, err := configDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "config.toml")
+}
+
+// loadConfig reads the persisted Config, returning zero-value defaults if
+// it hasn't been written yet.
+func loadConfig() Config {
+	var cfg Config
+
+	path := configPath()
+	if path == "" {
+		return cfg
+	}
+
+	if _, err := toml.DecodeFile(path, &cfg); err != nil && !os.IsNotExist(err) {
+		log.Printf("reading config %s: %v", path, err)
+	}
+
+	return cfg
+}
@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadToken resolves a provider token, preferring the environment variable
+// `envVar` and falling back to the `key = value` entry named `configKey` in
+// ~/.gogitconfig. Returns "" if neither is set.
+func LoadToken(envVar, configKey string) string {
+	if token := os.Getenv(envVar); token != "" {
+		return token
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	f, err := os.Open(filepath.Join(home, ".gogitconfig"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(key) == configKey {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const gitlabCalendarEndpoint = "https://gitlab.com/users/%s/calendar.json"
+
+// GitlabProvider fetches a user's contribution calendar from GitLab.
+type GitlabProvider struct {
+	Token string
+}
+
+// NewGitlabProvider builds a GitlabProvider authenticated with token.
+func NewGitlabProvider(token string) *GitlabProvider {
+	return &GitlabProvider{Token: token}
+}
+
+func (p *GitlabProvider) Name() string { return "gitlab" }
+
+// Fetch queries GitLab's per-user contribution calendar, keyed by username
+// (GitLab's calendar is per-user rather than per-email, so `email` is
+// treated as the username here), and filters it down to [since, until].
+func (p *GitlabProvider) Fetch(ctx context.Context, email string, since, until time.Time) (map[time.Time]int, error) {
+	endpoint := fmt.Sprintf(gitlabCalendarEndpoint, url.PathEscape(email))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	if p.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", p.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling gitlab: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gitlab returned %s", resp.Status)
+	}
+
+	var calendar map[string]int
+	if err := json.NewDecoder(resp.Body).Decode(&calendar); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	commits := make(map[time.Time]int)
+	for dateStr, count := range calendar {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			continue
+		}
+		if date.Before(since) || date.After(until) {
+			continue
+		}
+		commits[date] = count
+	}
+
+	return commits, nil
+}
@@ -0,0 +1,22 @@
+// Package providers fetches a developer's contribution history from
+// remote code-hosting services, so it can be merged with commits found in
+// local clones. This covers commits that never touch the laptop doing the
+// scanning: CI-only pushes, suggestions applied through a web UI, or
+// private mirrors the user doesn't have checked out.
+package providers
+
+import (
+	"context"
+	"time"
+)
+
+// Provider fetches a user's contribution counts from a remote service,
+// bucketed by day, for the window [since, until].
+type Provider interface {
+	// Name identifies the provider for logging and the --source flag.
+	Name() string
+
+	// Fetch returns contribution counts keyed by day (truncated to
+	// midnight UTC) for the given email within [since, until].
+	Fetch(ctx context.Context, email string, since, until time.Time) (map[time.Time]int, error)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Renderer turns a window of per-day commit counts, laid out as the
+// week/day grid built by buildCols, into an output format. `today` is the
+// row offset (as returned by calcOffset) of the current day within its
+// week, used to highlight it.
+type Renderer interface {
+	Render(cols map[int]column, today int) error
+}
+
+// newRenderer builds the Renderer for `format` (ansi, json, svg or html;
+// ansi is the default), writing to w.
+func newRenderer(format string, w io.Writer, weeks int, opts StatsOptions) (Renderer, error) {
+	switch format {
+	case "", "ansi":
+		return &ansiRenderer{w: w, weeks: weeks, opts: opts}, nil
+	case "json":
+		return &jsonRenderer{w: w, weeks: weeks, opts: opts}, nil
+	case "svg":
+		return &svgRenderer{w: w, weeks: weeks, opts: opts}, nil
+	case "html":
+		return &htmlRenderer{w: w, weeks: weeks, opts: opts}, nil
+	default:
+		return nil, fmt.Errorf("unknown --format %q (want ansi, json, svg or html)", format)
+	}
+}
+
+// dateForCell returns the calendar date of the cell at week `i`, weekday
+// row `j`, given the day `today` sits at within its own row.
+func dateForCell(opts StatsOptions, today, i, j int) time.Time {
+	k := i*7 + j
+	return opts.resolvedUntil().Add(time.Duration(today-k) * 24 * time.Hour)
+}
+
+// ansiRenderer reproduces the original escape-coded terminal heatmap.
+type ansiRenderer struct {
+	w     io.Writer
+	weeks int
+	opts  StatsOptions
+}
+
+func (r *ansiRenderer) Render(cols map[int]column, today int) error {
+	printCells(r.w, cols, r.weeks, today, r.opts)
+	return nil
+}
+
+// jsonRenderer emits one {date, count} record per day in the window, sorted
+// by date. Only days whose week is present in cols get a record: a week that
+// buildCols never completed (as the oldest week of the window can be) has none.
+type jsonRenderer struct {
+	w     io.Writer
+	weeks int
+	opts  StatsOptions
+}
+
+type dayCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+func (r *jsonRenderer) Render(cols map[int]column, today int) error {
+	var records []dayCount
+	since, until := r.opts.resolvedSince(), r.opts.resolvedUntil()
+
+	for i := r.weeks + 1; i >= 0; i-- {
+		col, ok := cols[i]
+		if !ok {
+			continue
+		}
+		for j, count := range col {
+			date := dateForCell(r.opts, today, i, j)
+			if date.Before(since) || date.After(until) {
+				continue
+			}
+			records = append(records, dayCount{Date: date.Format("2006-01-02"), Count: count})
+		}
+	}
+
+	sort.Slice(records, func(a, b int) bool { return records[a].Date < records[b].Date })
+
+	enc := json.NewEncoder(r.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+// svgRenderer renders a self-contained GitHub-style heatmap: one rect per
+// day, bucketed into the same four shades printCell uses, with month
+// labels along the top.
+type svgRenderer struct {
+	w     io.Writer
+	weeks int
+	opts  StatsOptions
+}
+
+const svgCell = 11
+const svgGap = 3
+const svgMargin = 20
+
+func svgBucketColor(val int) string {
+	switch {
+	case val == 0:
+		return "#ebedf0"
+	case val < 5:
+		return "#9be9a8"
+	case val < 10:
+		return "#40c463"
+	default:
+		return "#216e39"
+	}
+}
+
+func (r *svgRenderer) Render(cols map[int]column, today int) error {
+	width := (r.weeks+2)*(svgCell+svgGap) + svgMargin
+	height := 7*(svgCell+svgGap) + svgMargin
+
+	fmt.Fprintf(r.w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="10">`+"\n", width, height)
+
+	month := ""
+	for i := r.weeks + 1; i >= 0; i-- {
+		x := (r.weeks+1-i)*(svgCell+svgGap) + svgMargin
+
+		label := dateForCell(r.opts, today, i, 0).Month().String()[:3]
+		if label != month {
+			fmt.Fprintf(r.w, `<text x="%d" y="12">%s</text>`+"\n", x, label)
+			month = label
+		}
+
+		col, ok := cols[i]
+		if !ok {
+			continue
+		}
+		for j, count := range col {
+			y := j*(svgCell+svgGap) + svgMargin
+			fmt.Fprintf(r.w, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"/>`+"\n",
+				x, y, svgCell, svgCell, svgBucketColor(count))
+		}
+	}
+
+	fmt.Fprintf(r.w, "</svg>\n")
+	return nil
+}
+
+// htmlRenderer wraps the SVG heatmap in a minimal standalone page.
+type htmlRenderer struct {
+	w     io.Writer
+	weeks int
+	opts  StatsOptions
+}
+
+func (r *htmlRenderer) Render(cols map[int]column, today int) error {
+	fmt.Fprintf(r.w, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>Contribution graph</title></head><body>\n")
+
+	svg := &svgRenderer{w: r.w, weeks: r.weeks, opts: r.opts}
+	if err := svg.Render(cols, today); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(r.w, "</body></html>\n")
+	return nil
+}
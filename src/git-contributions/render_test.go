@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJSONRendererEmitsDateCountRecords(t *testing.T) {
+	opts := StatsOptions{Until: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}
+	today := calcOffset(opts.resolvedUntil())
+
+	cols := map[int]column{
+		0: {0, 0, 0, 0, 0, 0, 3},
+	}
+
+	var buf bytes.Buffer
+	r := &jsonRenderer{w: &buf, weeks: 0, opts: opts}
+	if err := r.Render(cols, today); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	var records []dayCount
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("unmarshal %q: %v", buf.String(), err)
+	}
+
+	var total int
+	for _, rec := range records {
+		total += rec.Count
+	}
+	if total != 3 {
+		t.Errorf("total count = %d, want 3", total)
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/go-git/go-git/v5"
 )
 
 // scanGitFolders returns a list of subfolders of `folder` ending with `.git`.
@@ -79,7 +82,7 @@ func recursiveFolderFind(folder string) []string {
 // parseFileLinesToSlice given a file path string, gets the content
 // of each line and parses it to a slice of strings.
 func parseExistingRepos() []string {
-	f := openFile(gitFile)
+	f := openFile(reposPath())
 	defer f.Close()
 
 	var lines []string
@@ -96,13 +99,14 @@ func parseExistingRepos() []string {
 	return lines
 }
 
-// openFile opens the file located at `filePath`. Creates it if not existing.
+// openFile opens the file located at `filePath` for reading. Creates it if
+// not existing.
 func openFile(filePath string) *os.File {
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0755)
+	f, err := os.OpenFile(filePath, os.O_RDONLY, 0755)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			// file does not exist
-			_, err = os.Create(filePath)
+			f, err = os.Create(filePath)
 			if err != nil {
 				panic(err)
 			}
@@ -136,11 +140,30 @@ func sliceContains(repoSlice []string, fileName string) bool {
 	return false
 }
 
-// updateDotFile writes content to the file in path `filePath`
-// (overwriting existing content)
+// updateDotFile overwrites the tracked-repos file with content, writing to
+// a sibling tempfile first and renaming it into place so a crash mid-write
+// can't truncate the list.
 func updateDotFile(repos []string) {
+	path := reposPath()
 	content := strings.Join(repos, "\n")
-	os.WriteFile(gitFile, []byte(content), 0755)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".repos.txt.*")
+	if err != nil {
+		panic(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		panic(err)
+	}
+	if err := tmp.Close(); err != nil {
+		panic(err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		panic(err)
+	}
 
 	fmt.Print(content)
 }
@@ -161,3 +184,61 @@ func scan(path string) {
 	addNewSliceElementsToFile(repositories)
 	fmt.Printf("\n\nSuccessfully added\n\n")
 }
+
+// removeRepos drops every tracked repo matching one of the given paths or
+// glob patterns (as understood by path/filepath's Match) from the dot file.
+func removeRepos(patterns []string) error {
+	existing := parseExistingRepos()
+	var kept []string
+	removed := 0
+
+	for _, repo := range existing {
+		matched := false
+		for _, pattern := range patterns {
+			ok, err := filepath.Match(pattern, repo)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			if ok {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			removed++
+			continue
+		}
+		kept = append(kept, repo)
+	}
+
+	updateDotFile(kept)
+	fmt.Printf("\nremoved %d repositories\n", removed)
+	return nil
+}
+
+// listRepos prints every tracked repository together with the date of its
+// most recent commit, one per line.
+func listRepos() error {
+	for _, path := range parseExistingRepos() {
+		repo, err := git.PlainOpen(path)
+		if err != nil {
+			fmt.Printf("%s\t(unreadable: %v)\n", path, err)
+			continue
+		}
+
+		ref, err := repo.Head()
+		if err != nil {
+			fmt.Printf("%s\t(no HEAD: %v)\n", path, err)
+			continue
+		}
+
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			fmt.Printf("%s\t(no HEAD commit: %v)\n", path, err)
+			continue
+		}
+
+		fmt.Printf("%s\t%s\n", path, commit.Author.When.Format("2006-01-02"))
+	}
+	return nil
+}
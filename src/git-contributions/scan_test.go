@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateDotFileAtomicRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	repos := []string{"/tmp/a", "/tmp/b"}
+	updateDotFile(repos)
+
+	got := parseExistingRepos()
+	if len(got) != len(repos) {
+		t.Fatalf("parseExistingRepos = %v, want %v", got, repos)
+	}
+	for i, r := range repos {
+		if got[i] != r {
+			t.Errorf("repo[%d] = %q, want %q", i, got[i], r)
+		}
+	}
+
+	// The rename-into-place must not leave its tempfile behind.
+	dir := filepath.Dir(reposPath())
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	for _, e := range entries {
+		if e.Name() != "repos.txt" {
+			t.Errorf("unexpected leftover file %q in %s", e.Name(), dir)
+		}
+	}
+}
+
+func TestRemoveReposMatchesGlob(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	updateDotFile([]string{"/repos/a", "/repos/b", "/other/c"})
+
+	if err := removeRepos([]string{"/repos/*"}); err != nil {
+		t.Fatalf("removeRepos: %v", err)
+	}
+
+	got := parseExistingRepos()
+	if len(got) != 1 || got[0] != "/other/c" {
+		t.Errorf("parseExistingRepos = %v, want [/other/c]", got)
+	}
+}
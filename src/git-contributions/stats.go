@@ -6,9 +6,17 @@ package main
 // libs like the libgit2 bindings do), which for my program is a good compromise.
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path"
+	"runtime"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
@@ -17,13 +25,142 @@ import (
 const outOfRange = 99999
 const daysInLastSixMonths = 183
 const weeksInLastSixMonths = 26
-const gitFile = ".gogitlocalstats"
 
 type column []int
 
-// printCell given a cell value prints it with a different format
+// repoResult is the outcome of walking a single repository's log, produced
+// by a worker and consumed by the reducer in processRepositories.
+type repoResult struct {
+	path    string
+	commits map[int]int
+	err     error
+}
+
+// StatsOptions controls which commits are counted and how the scan runs.
+// Since/Until default to the last six months (ending today) when left
+// zero, and Authors matches every author when left empty so multiple
+// identities (e.g. a work and a personal email) can be aggregated into one
+// graph by passing them all.
+type StatsOptions struct {
+	Since   time.Time
+	Until   time.Time
+	Authors []string
+	Paths   []string
+	Sources []string
+	Jobs    int
+	Format  string
+	Output  string
+}
+
+// resolvedUntil returns the end of the window, defaulting to today.
+func (o StatsOptions) resolvedUntil() time.Time {
+	if o.Until.IsZero() {
+		return getBeginningOfDay(time.Now())
+	}
+	return getBeginningOfDay(o.Until)
+}
+
+// resolvedSince returns the start of the window, defaulting to
+// daysInLastSixMonths before resolvedUntil.
+func (o StatsOptions) resolvedSince() time.Time {
+	if o.Since.IsZero() {
+		return o.resolvedUntil().Add(-daysInLastSixMonths * time.Hour * 24)
+	}
+	return getBeginningOfDay(o.Since)
+}
+
+// windowDays returns the number of days spanned by the resolved window.
+func (o StatsOptions) windowDays() int {
+	days := int(o.resolvedUntil().Sub(o.resolvedSince()).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// matchesAuthor reports whether email should be counted, matching every
+// author when Authors is empty.
+func (o StatsOptions) matchesAuthor(email string) bool {
+	if len(o.Authors) == 0 {
+		return true
+	}
+	for _, author := range o.Authors {
+		if author == email {
+			return true
+		}
+	}
+	return false
+}
+
+// pathFilter compiles Paths once into a predicate suitable for
+// git.LogOptions.PathFilter, matching a changed file against any of the
+// glob patterns using doublestar semantics: "**" matches zero or more path
+// segments, "*" matches within a single segment (path.Match otherwise), and
+// a bare directory name (no wildcards) also matches anything beneath it.
+// Returns nil when Paths is empty, leaving PathFilter unset so every
+// commit is counted.
+func (o StatsOptions) pathFilter() func(string) bool {
+	if len(o.Paths) == 0 {
+		return nil
+	}
+
+	patterns := o.Paths
+	return func(changed string) bool {
+		for _, pattern := range patterns {
+			if doublestarMatch(pattern, changed) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// doublestarMatch reports whether changed matches pattern. A trailing "/"
+// or a pattern without any wildcard is treated as a directory prefix
+// (matching the path itself and everything beneath it); otherwise "**"
+// matches across path segments and "*" matches within one, per segment.
+func doublestarMatch(pattern, changed string) bool {
+	if !strings.Contains(pattern, "*") {
+		prefix := strings.TrimSuffix(pattern, "/")
+		return changed == prefix || strings.HasPrefix(changed, prefix+"/")
+	}
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	return doublestarMatchSegments(strings.Split(pattern, "/"), strings.Split(changed, "/"))
+}
+
+// doublestarMatchSegments matches a pattern against a path, both already
+// split on "/", where a "**" segment consumes zero or more path segments.
+func doublestarMatchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		if doublestarMatchSegments(pattern[1:], name) {
+			return true
+		}
+		if len(name) == 0 {
+			return false
+		}
+		return doublestarMatchSegments(pattern, name[1:])
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+
+	return doublestarMatchSegments(pattern[1:], name[1:])
+}
+
+// printCell given a cell value writes it to w with a different format
 // based on the value amount, and on the `today` flag.
-func printCell(val int, today bool) {
+func printCell(w io.Writer, val int, today bool) {
 	escape := "\033[0;37;30m"
 	switch {
 	case val > 0 && val < 5:
@@ -39,7 +176,7 @@ func printCell(val int, today bool) {
 	}
 
 	if val == 0 {
-		fmt.Printf(escape + "  - " + "\033[0m")
+		fmt.Fprintf(w, escape+"  - "+"\033[0m")
 		return
 	}
 
@@ -51,80 +188,84 @@ func printCell(val int, today bool) {
 		str = "%d "
 	}
 
-	fmt.Printf(escape+str+"\033[0m", val)
+	fmt.Fprintf(w, escape+str+"\033[0m", val)
 }
 
-// printDayCol given the day number (0 is Sunday) prints the day name,
+// printDayCol given the day number (0 is Sunday) writes the day name to w,
 // alternating the rows (prints just 1,3,5)
-func printDayCol(day int) {
+func printDayCol(w io.Writer, day int) {
 	switch day {
 	case 5:
-		fmt.Printf(" Fri ")
+		fmt.Fprintf(w, " Fri ")
 	case 3:
-		fmt.Printf(" Wed")
+		fmt.Fprintf(w, " Wed")
 	case 1:
-		fmt.Printf(" Mon ")
+		fmt.Fprintf(w, " Mon ")
 	default:
-		fmt.Printf("     ")
+		fmt.Fprintf(w, "     ")
 	}
 
 }
 
-// printMonths prints the month names in the first line, determining when the month
-// changed between switching weeks
-func printMonths() {
-	week := getBeginningOfDay(time.Now()).Add(-(daysInLastSixMonths * time.Hour * 24))
+// printMonths writes the month names in the first line, determining when
+// the month changed between switching weeks, across the window described
+// by opts.
+func printMonths(w io.Writer, opts StatsOptions) {
+	week := opts.resolvedSince()
+	until := opts.resolvedUntil()
 	month := week.Month()
-	fmt.Printf("         ")
+	fmt.Fprintf(w, "         ")
 
 	for {
 		if week.Month() != month {
-			fmt.Printf("%s ", week.Month().String()[:3])
+			fmt.Fprintf(w, "%s ", week.Month().String()[:3])
 			month = week.Month()
 		} else {
-			fmt.Printf("    ")
+			fmt.Fprintf(w, "    ")
 		}
 
 		week = week.Add(7 * time.Hour * 24)
-		if week.After(time.Now()) {
+		if week.After(until) {
 			break
 		}
 	}
 
-	fmt.Print("\n")
+	fmt.Fprint(w, "\n")
 }
 
-// printCells prints the cells of the graph
-func printCells(cols map[int]column) {
+// printCells writes the cells of the graph to w, spanning weeks+2 columns
+// and using `opts`/`today` to place today's marker correctly within the
+// window.
+func printCells(w io.Writer, cols map[int]column, weeks int, today int, opts StatsOptions) {
 	// print graph header
-	printMonths()
+	printMonths(w, opts)
 
 	// loop through days (rows) and weeks (cols)
 	for j := 6; j >= 0; j-- {
-		for i := weeksInLastSixMonths + 1; i >= 0; i-- {
+		for i := weeks + 1; i >= 0; i-- {
 
 			// the first column is the days of the week
-			if i == weeksInLastSixMonths+1 {
-				printDayCol(j)
+			if i == weeks+1 {
+				printDayCol(w, j)
 			}
 
 			// accessing current of current week in cols
 			// cols[i] is ith week in cols
 			if col, ok := cols[i]; ok {
 				// special case if its today
-				if i == 0 && j == calcOffset()-1 {
-					printCell(col[j], true)
+				if i*7+j == today {
+					printCell(w, col[j], true)
 					continue
 				} else {
 					if len(col) > j {
-						printCell(col[j], false)
+						printCell(w, col[j], false)
 						continue
 					}
 				}
 			}
-			printCell(0, false)
+			printCell(w, 0, false)
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(w, "\n")
 	}
 }
 
@@ -143,7 +284,7 @@ func buildCols(keys []int, commits map[int]int) map[int]column {
 		}
 
 		// add the commit count for day `k` to `col`
-		// before appending column to entire map of commits in the last 6 months
+		// before appending column to entire map of commits in the window
 		col = append(col, commits[k])
 
 		// sunday start of the week
@@ -168,20 +309,22 @@ func sortMapIntoSlice(m map[int]int) []int {
 	return keys
 }
 
-// printCommitsStats prints the commits stats
-func printCommitsStats(commits map[int]int) {
+// printCommitsStats hands the commits stats for the window described by
+// opts to renderer, which is responsible for producing whatever output
+// format (ansi, json, svg, html, ...) the caller asked for.
+func printCommitsStats(commits map[int]int, opts StatsOptions, renderer Renderer) error {
 	keys := sortMapIntoSlice(commits)
 	columns := buildCols(keys, commits)
-	printCells(columns)
+	today := calcOffset(opts.resolvedUntil())
+	return renderer.Render(columns, today)
 }
 
 // calcOffset determines and returns the amount of days missing to fill
-// the last row of the stats graph
-func calcOffset() int {
+// the last row of the stats graph, ending on `until`.
+func calcOffset(until time.Time) int {
 	var offset int
-	weekday := time.Now().Weekday()
 
-	switch weekday {
+	switch until.Weekday() {
 	case time.Sunday:
 		offset = 7
 	case time.Saturday:
@@ -208,85 +351,225 @@ func getBeginningOfDay(t time.Time) time.Time {
 	return t
 }
 
-// countDaysSinceDate counts how many days passed since the passed `date`
-func countDaysSinceDate(date time.Time) int {
+// countDaysSinceDate counts how many days passed between `date` and
+// `until` (the end of the window, not necessarily today), returning
+// outOfRange once that exceeds maxDays.
+func countDaysSinceDate(date, until time.Time, maxDays int) int {
 	days := 0
-	now := getBeginningOfDay(time.Now())
-	//
-	for date.Before(now) {
+	for date.Before(until) {
 		date = date.Add(time.Hour * 24)
 		days++
-		if days > daysInLastSixMonths {
+		if days > maxDays {
 			return outOfRange
 		}
 	}
 	return days
 }
 
-// fillCommits given a repository found in `path`, gets the commits and
-// puts them in the `commits` map, returning it when completed
-func fillCommits(email string, path string, commits map[int]int) map[int]int {
-	// instantiate a git repo object from path
+// fillCommits opens the repository at `path` and walks its commit history
+// within opts' Since/Until window, counting commits authored by one of
+// opts.Authors into a per-repo `map[int]int` keyed by days-ago. The walk is
+// bounded by `ctx`: if ctx is cancelled mid-walk, the partial result
+// gathered so far is returned alongside the context error instead of
+// panicking.
+func fillCommits(ctx context.Context, opts StatsOptions, path string) (map[int]int, error) {
 	repo, err := git.PlainOpen(path)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("opening repo %s: %w", path, err)
 	}
-	// get the HEAD reference
+
 	ref, err := repo.Head()
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("reading HEAD of %s: %w", path, err)
 	}
-	// get the commits history starting from HEAD
-	iterator, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+
+	logOptions := &git.LogOptions{From: ref.Hash()}
+	if !opts.Since.IsZero() {
+		since := opts.resolvedSince()
+		logOptions.Since = &since
+	}
+	if !opts.Until.IsZero() {
+		// go-git skips commits after Until, so it is set to the end of the
+		// Until day to keep the commits made on that day.
+		until := opts.resolvedUntil().Add(24*time.Hour - time.Nanosecond)
+		logOptions.Until = &until
+	}
+	if filter := opts.pathFilter(); filter != nil {
+		logOptions.PathFilter = filter
+	}
+
+	iterator, err := repo.Log(logOptions)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("walking log of %s: %w", path, err)
 	}
 
-	// iterate the commits
-	offset := calcOffset()
+	commits := make(map[int]int)
+	offset := calcOffset(opts.resolvedUntil())
+	maxDays := opts.windowDays()
+
 	for {
+		select {
+		case <-ctx.Done():
+			return commits, ctx.Err()
+		default:
+		}
+
 		commit, err := iterator.Next()
 		if err != nil {
 			if err == io.EOF {
 				break // End of commit history
 			}
-			panic(err) // Handle error appropriately
+			return commits, fmt.Errorf("iterating commits of %s: %w", path, err)
 		}
 
-		// Filter by author's email
-		if commit.Author.Email != email {
+		// Filter by author's email(s)
+		if !opts.matchesAuthor(commit.Author.Email) {
 			continue
 		}
 
-		// Calculate the number of days ago for each commit
-		daysAgo := countDaysSinceDate(commit.Author.When) + offset
+		// Calculate the number of days ago for each commit, anchored on
+		// the same window end the offset and renderer use.
+		daysAgo := countDaysSinceDate(commit.Author.When, opts.resolvedUntil(), maxDays)
 		if daysAgo != outOfRange {
+			daysAgo += offset
 			commits[daysAgo]++
 		}
 	}
 
-	return commits
+	return commits, nil
 }
 
-// processRepositories given an user email, returns the
-// commits made in the last 6 months
-func processRepositories(email string) map[int]int {
+// processRepositories returns the commits made within opts' window across
+// every tracked repository. Repositories are walked concurrently by a pool
+// of opts.Jobs workers (defaulting to runtime.NumCPU() when not positive);
+// a single reducer merges each worker's per-repo result into the final map.
+// A repo that can't be opened or walked is logged and skipped rather than
+// aborting the whole run.
+func processRepositories(ctx context.Context, opts StatsOptions) map[int]int {
 	repos := parseExistingRepos()
 
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	paths := make(chan string)
+	results := make(chan repoResult)
+
+	var workers sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for path := range paths {
+				commits, err := fillCommits(ctx, opts, path)
+				results <- repoResult{path: path, commits: commits, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(paths)
+		for _, path := range repos {
+			select {
+			case paths <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
 	commits := make(map[int]int)
-	for i := 0; i < daysInLastSixMonths; i++ {
+	for i := 0; i <= opts.windowDays(); i++ {
 		commits[i] = 0
 	}
 
-	for _, path := range repos {
-		commits = fillCommits(email, path, commits)
+	scanned, skipped := 0, 0
+	for res := range results {
+		if res.err != nil {
+			log.Printf("%s: skipped (%v)", res.path, res.err)
+			skipped++
+			continue
+		}
+
+		total := 0
+		for day, count := range res.commits {
+			commits[day] += count
+			total += count
+		}
+		log.Printf("%s: %d commits", res.path, total)
+		scanned++
 	}
+	fmt.Printf("scanned %d repositories (%d skipped)\n", scanned, skipped)
 
 	return commits
 }
 
-// stats calculates and prints the stats.
-func stats(email string) {
-	commits := processRepositories(email)
-	printCommitsStats(commits)
-}
\ No newline at end of file
+// stats calculates and renders the stats for opts, aggregating commits
+// found in tracked local repositories with commits reported by any remote
+// providers named in opts.Sources (defaulting to just "local"). Output goes
+// to opts.Output (stdout when empty) in opts.Format (ansi when empty). The
+// scan is cancelled early if the user sends an interrupt (Ctrl-C) while
+// repositories are still being walked.
+func stats(opts StatsOptions) {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	sources := opts.Sources
+	if len(sources) == 0 {
+		sources = []string{"local"}
+	}
+
+	var commits map[int]int
+	if containsSource(sources, "local") {
+		commits = processRepositories(ctx, opts)
+	} else {
+		commits = make(map[int]int)
+		for i := 0; i <= opts.windowDays(); i++ {
+			commits[i] = 0
+		}
+	}
+
+	authors := opts.Authors
+	if len(authors) == 0 {
+		authors = []string{""}
+	}
+
+	for _, provider := range buildProviders(sources) {
+		for _, author := range authors {
+			remote, err := provider.Fetch(ctx, author, opts.resolvedSince(), opts.resolvedUntil())
+			if err != nil {
+				log.Printf("%s: %v", provider.Name(), err)
+				continue
+			}
+			mergeProviderCommits(commits, opts, remote)
+		}
+	}
+
+	out := io.Writer(os.Stdout)
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			log.Printf("opening --output %s: %v", opts.Output, err)
+			return
+		}
+		defer f.Close()
+		out = f
+	}
+
+	weeks := opts.windowDays()/7 + 1
+	renderer, err := newRenderer(opts.Format, out, weeks, opts)
+	if err != nil {
+		log.Printf("%v", err)
+		return
+	}
+
+	if err := printCommitsStats(commits, opts, renderer); err != nil {
+		log.Printf("rendering stats: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDoublestarMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		changed string
+		want    bool
+	}{
+		{"frontend/", "frontend/src/app.js", true},
+		{"frontend/", "backend/src/app.js", false},
+		{"frontend", "frontend/src/app.js", true},
+		{"frontend", "frontend", true},
+		{"frontend", "backend/src/app.js", false},
+		{"*.go", "main.go", true},
+		{"*.go", "pkg/main.go", false},
+		{"**/*.go", "pkg/sub/main.go", true},
+		{"**/*.go", "main.go", true},
+		{"pkg/**", "pkg/sub/file.txt", true},
+		{"pkg/**", "other/file.txt", false},
+	}
+
+	for _, c := range cases {
+		if got := doublestarMatch(c.pattern, c.changed); got != c.want {
+			t.Errorf("doublestarMatch(%q, %q) = %v, want %v", c.pattern, c.changed, got, c.want)
+		}
+	}
+}
+
+func TestCountDaysSinceDateAnchoredOnUntil(t *testing.T) {
+	until := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := countDaysSinceDate(until.Add(-10*24*time.Hour), until, 30); got != 10 {
+		t.Errorf("countDaysSinceDate = %d, want 10", got)
+	}
+
+	if got := countDaysSinceDate(until.Add(-40*24*time.Hour), until, 30); got != outOfRange {
+		t.Errorf("countDaysSinceDate = %d, want outOfRange", got)
+	}
+}
+
+func TestMergeProviderCommitsAnchoredOnUntil(t *testing.T) {
+	opts := StatsOptions{
+		Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	commits := make(map[int]int)
+	for i := 0; i <= opts.windowDays(); i++ {
+		commits[i] = 0
+	}
+
+	remote := map[time.Time]int{
+		opts.resolvedUntil().Add(-5 * 24 * time.Hour): 2,
+	}
+
+	mergeProviderCommits(commits, opts, remote)
+
+	offset := calcOffset(opts.resolvedUntil())
+	want := 5 + offset
+	if commits[want] != 2 {
+		t.Errorf("commits[%d] = %d, want 2", want, commits[want])
+	}
+}